@@ -0,0 +1,256 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StoreKind represents the kind of a (Cluster)SecretStore, used to decide how
+// namespaced references (e.g. CAProvider.Namespace) should be resolved.
+const (
+	SecretStoreKind        = "SecretStore"
+	ClusterSecretStoreKind = "ClusterSecretStore"
+)
+
+// CAProviderType determines the type of the CAProvider.
+// +kubebuilder:validation:Enum="Secret";"ConfigMap"
+type CAProviderType string
+
+const (
+	CAProviderTypeSecret    CAProviderType = "Secret"
+	CAProviderTypeConfigMap CAProviderType = "ConfigMap"
+)
+
+// CAProviderMode controls how the CA certificates resolved from a CAProvider
+// are combined with the OS trust store when building the pool used to
+// validate the Vault server's certificate.
+// +kubebuilder:validation:Enum="Replace";"Append";"SystemOnly"
+type CAProviderMode string
+
+const (
+	// CAProviderModeReplace uses only the certificates resolved from the
+	// CAProvider, ignoring the OS trust store. This is the default and
+	// matches the historical behavior.
+	CAProviderModeReplace CAProviderMode = "Replace"
+
+	// CAProviderModeAppend starts from the OS trust store and adds the
+	// certificates resolved from the CAProvider.
+	CAProviderModeAppend CAProviderMode = "Append"
+
+	// CAProviderModeSystemOnly ignores the CAProvider entirely and
+	// validates against the OS trust store only.
+	CAProviderModeSystemOnly CAProviderMode = "SystemOnly"
+)
+
+// CAProvider lets you specify a reference to a CA certificate bundle that is
+// used to validate the Vault server's TLS certificate.
+type CAProvider struct {
+	// The type of provider to use such as "Secret", or "ConfigMap".
+	Type CAProviderType `json:"type"`
+
+	// The name of the object located at the provider type.
+	Name string `json:"name"`
+
+	// The key the value inside of the provider type to use, only used with "Secret" type.
+	// +optional
+	Key string `json:"key,omitempty"`
+
+	// The namespace the Provider type is in.
+	// Can only be defined when used in a ClusterSecretStore.
+	// +optional
+	Namespace *string `json:"namespace,omitempty"`
+
+	// Mode controls how the resolved CA certificates are combined with the
+	// OS trust store. Defaults to "Replace".
+	// +optional
+	Mode CAProviderMode `json:"mode,omitempty"`
+}
+
+// SecretKeySelector contains enough information to let you locate the
+// referenced Secret key inside the same namespace as the referent (for
+// SecretStore) or a configurable namespace (for ClusterSecretStore).
+type SecretKeySelector struct {
+	// The name of the Secret resource being referred to.
+	Name string `json:"name,omitempty"`
+
+	// The namespace the Secret resource is in.
+	// Can only be defined when used in a ClusterSecretStore.
+	// +optional
+	Namespace *string `json:"namespace,omitempty"`
+
+	// The key of the entry in the Secret resource's `data` field to be used.
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// VaultProvider configures a store to sync secrets using a HashiCorp Vault
+// KV backend.
+type VaultProvider struct {
+	// Server is the connection address for the Vault server, e.g:
+	// "https://vault.example.com:8200".
+	Server string `json:"server"`
+
+	// Path is the mount path of the Vault KV backend endpoint, e.g:
+	// "secret".
+	// +optional
+	Path *string `json:"path,omitempty"`
+
+	// Version is the Vault KV secret engine version. This can be either "v1"
+	// or "v2". Version defaults to "v2".
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Namespace, if set, is used as the Vault enterprise namespace. Namespace
+	// is supplied via the X-Vault-Namespace header.
+	// +optional
+	Namespace *string `json:"namespace,omitempty"`
+
+	// CABundle is a PEM encoded CA bundle used to validate the certificate
+	// presented by the Vault server.
+	// +optional
+	CABundle []byte `json:"caBundle,omitempty"`
+
+	// CAProvider is a reference to a ConfigMap or Secret containing the CA
+	// bundle used to validate the certificate presented by the Vault server.
+	// +optional
+	CAProvider *CAProvider `json:"caProvider,omitempty"`
+
+	// CABundleReloadInterval, when set, enables periodic re-reading of the
+	// CAProvider source so that CA rotations are picked up without
+	// restarting the controller. Defaults to 5m when set to zero.
+	// Reload is disabled unless this field is set.
+	// +optional
+	CABundleReloadInterval *metav1.Duration `json:"caBundleReloadInterval,omitempty"`
+
+	// CAPinSHA256 pins one or more acceptable SHA-256 hashes (hex encoded)
+	// of the Vault server's CA certificate DER, or of the leaf
+	// certificate's SubjectPublicKeyInfo. When set, the presented
+	// certificate chain must contain at least one certificate matching a
+	// pin, in addition to any other configured validation. Pins may also
+	// be supplied via a "#sha256:<hex>[,<hex>...]" fragment on Server.
+	// +optional
+	CAPinSHA256 []string `json:"caPinSHA256,omitempty"`
+
+	// ClientTLS configures a client certificate and key used for mutual TLS
+	// authentication to the Vault server. The same material is reused by
+	// the "cert" auth method when configured.
+	// +optional
+	ClientTLS *ClientTLS `json:"clientTls,omitempty"`
+
+	// HTTPClient tunes the underlying HTTP transport and retry/backoff
+	// behavior used to talk to the Vault server.
+	// +optional
+	HTTPClient *VaultHTTPClient `json:"httpClient,omitempty"`
+}
+
+// VaultHTTPClient tunes the HTTP transport and retry behavior of the Vault
+// API client.
+type VaultHTTPClient struct {
+	// Timeout is the overall timeout for a single Vault request. Defaults
+	// to the hashicorp/vault/api client's own default when unset.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// TLSHandshakeTimeout bounds how long the TLS handshake may take.
+	// +optional
+	TLSHandshakeTimeout *metav1.Duration `json:"tlsHandshakeTimeout,omitempty"`
+
+	// IdleConnTimeout bounds how long an idle keep-alive connection is
+	// kept in the pool.
+	// +optional
+	IdleConnTimeout *metav1.Duration `json:"idleConnTimeout,omitempty"`
+
+	// MaxIdleConnsPerHost caps the number of idle keep-alive connections
+	// kept per Vault host.
+	// +optional
+	MaxIdleConnsPerHost int `json:"maxIdleConnsPerHost,omitempty"`
+
+	// DisableKeepAlives disables HTTP keep-alives to the Vault server.
+	// +optional
+	DisableKeepAlives bool `json:"disableKeepAlives,omitempty"`
+
+	// Proxy configures the proxy used for outbound requests to Vault.
+	// +optional
+	Proxy *VaultProxyConfig `json:"proxy,omitempty"`
+
+	// Retry configures per-request retry/backoff behavior.
+	// +optional
+	Retry *VaultRetryConfig `json:"retry,omitempty"`
+
+	// Headers are added to every request made to Vault, e.g. for a
+	// per-store X-Vault-Namespace override or custom audit headers.
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// VaultProxyConfig configures the proxy used for outbound requests to Vault.
+type VaultProxyConfig struct {
+	// URL is an explicit proxy URL to use. Ignored when FromEnvironment is
+	// set.
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// FromEnvironment uses the standard HTTP_PROXY/HTTPS_PROXY/NO_PROXY
+	// environment variables, via http.ProxyFromEnvironment.
+	// +optional
+	FromEnvironment bool `json:"fromEnvironment,omitempty"`
+
+	// NoProxy lists hostnames that should bypass URL, when set.
+	// +optional
+	NoProxy []string `json:"noProxy,omitempty"`
+}
+
+// VaultRetryConfig configures per-request retry/backoff behavior, mapped
+// onto api.Config's MaxRetries/MinRetryWait/MaxRetryWait/CheckRetry.
+type VaultRetryConfig struct {
+	// MaxRetries is the maximum number of retry attempts. A pointer so 0
+	// can be set explicitly to disable retries, distinct from leaving this
+	// unset to keep the vault client's default.
+	// +optional
+	MaxRetries *int `json:"maxRetries,omitempty"`
+
+	// MinBackoff is the minimum wait between retries.
+	// +optional
+	MinBackoff *metav1.Duration `json:"minBackoff,omitempty"`
+
+	// MaxBackoff is the maximum wait between retries.
+	// +optional
+	MaxBackoff *metav1.Duration `json:"maxBackoff,omitempty"`
+
+	// RetryOn5xx retries requests that receive a 5xx response. Defaults to
+	// true, matching the vault client's own default retry policy, unless
+	// explicitly set to false.
+	// +optional
+	RetryOn5xx *bool `json:"retryOn5xx,omitempty"`
+
+	// RetryOnConnectionError retries requests that fail before receiving a
+	// response, e.g. due to a dropped connection. Defaults to true,
+	// matching the vault client's own default retry policy, unless
+	// explicitly set to false.
+	// +optional
+	RetryOnConnectionError *bool `json:"retryOnConnectionError,omitempty"`
+}
+
+// ClientTLS references the Kubernetes Secret keys holding a PEM encoded
+// client certificate and private key used for mutual TLS to Vault.
+type ClientTLS struct {
+	// CertSecretRef is a certificate added to the transport layer
+	// when communicating with the Vault server.
+	CertSecretRef *SecretKeySelector `json:"certSecretRef,omitempty"`
+
+	// KeySecretRef to a key used for the CertSecretRef.
+	KeySecretRef *SecretKeySelector `json:"keySecretRef,omitempty"`
+}
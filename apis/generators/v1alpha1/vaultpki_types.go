@@ -0,0 +1,189 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+// VaultPKIKind is the Kind used in ExternalSecret
+// dataFrom.sourceRef.generatorRef.kind to reference a VaultPKI generator.
+const VaultPKIKind = "VaultPKI"
+
+// VaultPKIGroupVersionKind identifies the VaultPKI generator type.
+var VaultPKIGroupVersionKind = schema.GroupVersionKind{
+	Group:   GroupVersion.Group,
+	Version: GroupVersion.Version,
+	Kind:    VaultPKIKind,
+}
+
+// VaultPKISpec configures issuing or signing a short-lived X.509
+// certificate from a Vault pki secrets engine mount.
+type VaultPKISpec struct {
+	// Provider holds the Vault connection settings used to reach the pki
+	// secrets engine, the same schema the Vault SecretStore provider uses.
+	Provider esv1.VaultProvider `json:"provider"`
+
+	// Mount is the path the pki secrets engine is mounted at, e.g. "pki".
+	Mount string `json:"mount"`
+
+	// Role is the pki role to issue or sign against.
+	Role string `json:"role"`
+
+	// CommonName is the certificate's common name.
+	// +optional
+	CommonName string `json:"commonName,omitempty"`
+
+	// AltNames are additional DNS subject alternative names.
+	// +optional
+	AltNames []string `json:"altNames,omitempty"`
+
+	// IPSANs are IP address subject alternative names.
+	// +optional
+	IPSANs []string `json:"ipSans,omitempty"`
+
+	// URISANs are URI subject alternative names.
+	// +optional
+	URISANs []string `json:"uriSans,omitempty"`
+
+	// TTL is the requested certificate lifetime, e.g. "72h".
+	// +optional
+	TTL string `json:"ttl,omitempty"`
+
+	// Format is one of "pem", "pem_bundle" or "der". Defaults to "pem".
+	// +optional
+	Format string `json:"format,omitempty"`
+
+	// CSRRef, when set, causes VaultPKI to sign the referenced CSR via the
+	// "sign" (or "sign-verbatim") endpoint instead of having Vault
+	// generate a new keypair via "issue".
+	// +optional
+	CSRRef *esv1.SecretKeySelector `json:"csrRef,omitempty"`
+
+	// Verbatim routes a CSRRef sign request through Vault's
+	// "sign-verbatim" endpoint, using the CSR's subject and SAN fields
+	// as-is instead of constraining them to Role.
+	// +optional
+	Verbatim bool `json:"verbatim,omitempty"`
+}
+
+// VaultPKI issues or signs a short-lived X.509 certificate from a Vault pki
+// secrets engine mount, for use as an ExternalSecret
+// dataFrom.sourceRef.generatorRef source.
+type VaultPKI struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec VaultPKISpec `json:"spec"`
+}
+
+// VaultPKIList contains a list of VaultPKI resources.
+type VaultPKIList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []VaultPKI `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VaultPKI{}, &VaultPKIList{})
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *VaultPKISpec) DeepCopyInto(out *VaultPKISpec) {
+	*out = *in
+	out.Provider = in.Provider
+	if in.Provider.CABundle != nil {
+		out.Provider.CABundle = append([]byte(nil), in.Provider.CABundle...)
+	}
+	if in.AltNames != nil {
+		out.AltNames = append([]string(nil), in.AltNames...)
+	}
+	if in.IPSANs != nil {
+		out.IPSANs = append([]string(nil), in.IPSANs...)
+	}
+	if in.URISANs != nil {
+		out.URISANs = append([]string(nil), in.URISANs...)
+	}
+	if in.CSRRef != nil {
+		ref := *in.CSRRef
+		out.CSRRef = &ref
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *VaultPKISpec) DeepCopy() *VaultPKISpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultPKISpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *VaultPKI) DeepCopyInto(out *VaultPKI) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *VaultPKI) DeepCopy() *VaultPKI {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultPKI)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *VaultPKI) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *VaultPKIList) DeepCopyInto(out *VaultPKIList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		out.Items = make([]VaultPKI, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *VaultPKIList) DeepCopy() *VaultPKIList {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultPKIList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *VaultPKIList) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
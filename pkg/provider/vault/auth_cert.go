@@ -0,0 +1,52 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"context"
+	"fmt"
+
+	vault "github.com/hashicorp/vault/api"
+)
+
+// certAuthLogin performs a Vault "cert" auth login at
+// auth/<authMountPath>/login. Vault's cert auth method authenticates the
+// caller using the client certificate presented during the TLS handshake
+// itself, so this simply issues the login call against a vault.Client whose
+// transport already carries the ClientTLS material configured on the store
+// (see client.clientCertificate); there is no separate certificate payload
+// to send in the request body.
+func (c *client) certAuthLogin(ctx context.Context, authMountPath, role string) (*vault.Secret, error) {
+	if c.store.ClientTLS == nil {
+		return nil, fmt.Errorf("cert auth method requires clientTls to be configured on the VaultProvider")
+	}
+
+	vc, err := c.newVaultClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build vault client for cert auth: %w", err)
+	}
+
+	data := map[string]interface{}{}
+	if role != "" {
+		data["name"] = role
+	}
+
+	path := fmt.Sprintf("auth/%s/login", authMountPath)
+	secret, err := vc.Logical().WriteWithContext(ctx, path, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to log in via cert auth at %q: %w", path, err)
+	}
+	return secret, nil
+}
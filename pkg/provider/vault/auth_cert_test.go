@@ -0,0 +1,97 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+func TestCertAuthLogin_RequiresClientTLS(t *testing.T) {
+	c := &client{
+		store:     &esv1.VaultProvider{Server: "https://vault.example.com"},
+		storeKind: esv1.SecretStoreKind,
+	}
+
+	_, err := c.certAuthLogin(context.Background(), "cert", "")
+	require.Error(t, err)
+}
+
+// TestCertAuthLogin_PostsToAuthMountLoginPath verifies that, with ClientTLS
+// resolvable from a Secret, certAuthLogin builds its client and posts to the
+// configured auth mount's login path. The test server is plain HTTP, so the
+// client certificate is not actually presented, and the request body is not
+// inspected.
+func TestCertAuthLogin_PostsToAuthMountLoginPath(t *testing.T) {
+	certPEM, keyPEM := generateSelfSignedKeyPairPEM(t, "client.example.com")
+
+	var gotPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"auth":{"client_token":"t-123"}}`))
+	}))
+	defer srv.Close()
+
+	fakeClient := fake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "client-tls", Namespace: "ns"},
+		Data: map[string][]byte{
+			"tls.crt": certPEM,
+			"tls.key": keyPEM,
+		},
+	}).Build()
+
+	c := &client{
+		kube: fakeClient,
+		store: &esv1.VaultProvider{
+			Server: srv.URL,
+			ClientTLS: &esv1.ClientTLS{
+				CertSecretRef: &esv1.SecretKeySelector{Name: "client-tls", Key: "tls.crt"},
+				KeySecretRef:  &esv1.SecretKeySelector{Name: "client-tls", Key: "tls.key"},
+			},
+		},
+		storeKind: esv1.SecretStoreKind,
+		namespace: "ns",
+	}
+
+	secret, err := c.certAuthLogin(context.Background(), "cert", "my-role")
+	require.NoError(t, err)
+	require.NotNil(t, secret)
+	require.Equal(t, "/v1/auth/cert/login", gotPath)
+}
+
+func generateSelfSignedKeyPairPEM(t *testing.T, cn string) (certPEM, keyPEM []byte) {
+	t.Helper()
+
+	cert, key := generateSelfSignedCA(t, cn)
+
+	keyBytes, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes})
+	return certPEM, keyPEM
+}
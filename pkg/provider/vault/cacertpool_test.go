@@ -0,0 +1,95 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+func certToPEM(cert *x509.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+}
+
+func caProviderClient(t *testing.T, mode esv1.CAProviderMode) *client {
+	t.Helper()
+
+	ca, _ := generateSelfSignedCA(t, "ca")
+	fakeClient := fake.NewClientBuilder().WithObjects(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "ca-bundle", Namespace: "ns"},
+		Data:       map[string]string{"ca.crt": string(certToPEM(ca))},
+	}).Build()
+
+	return &client{
+		kube:      fakeClient,
+		namespace: "ns",
+		storeKind: esv1.SecretStoreKind,
+		store: &esv1.VaultProvider{
+			CAProvider: &esv1.CAProvider{
+				Type: esv1.CAProviderTypeConfigMap,
+				Name: "ca-bundle",
+				Key:  "ca.crt",
+				Mode: mode,
+			},
+		},
+	}
+}
+
+func TestCACertPool_ModeReplace_IgnoresSystemPool(t *testing.T) {
+	c := caProviderClient(t, esv1.CAProviderModeReplace)
+
+	pool, err := c.caCertPool(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, pool)
+	// A replace-mode pool only has room for the single CAProvider cert, so
+	// it must not also carry the (likely much larger) system trust store.
+	require.Len(t, pool.Subjects(), 1) //nolint:staticcheck // Subjects is deprecated but adequate for a count check in tests
+}
+
+func TestCACertPool_ModeAppend_IncludesCAProviderCert(t *testing.T) {
+	c := caProviderClient(t, esv1.CAProviderModeAppend)
+
+	pool, err := c.caCertPool(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, pool)
+	require.GreaterOrEqual(t, len(pool.Subjects()), 1) //nolint:staticcheck
+}
+
+func TestCACertPool_ModeSystemOnly_SkipsCAProviderFetch(t *testing.T) {
+	c := caProviderClient(t, esv1.CAProviderModeSystemOnly)
+	// Point the CAProvider at a ConfigMap that doesn't exist; SystemOnly
+	// must never try to resolve it.
+	c.store.CAProvider.Name = "does-not-exist"
+
+	_, err := c.caCertPool(context.Background())
+	require.NoError(t, err)
+}
+
+func TestCACertPool_NoCABundleOrProvider_ReturnsNilPool(t *testing.T) {
+	c := &client{store: &esv1.VaultProvider{}}
+
+	pool, err := c.caCertPool(context.Background())
+	require.NoError(t, err)
+	require.Nil(t, pool)
+}
@@ -0,0 +1,284 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	vault "github.com/hashicorp/vault/api"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+// client wraps the Hashicorp Vault API client together with the
+// (Cluster)SecretStore state needed to build and refresh its configuration.
+type client struct {
+	kube      kclient.Client
+	store     *esv1.VaultProvider
+	storeKind string
+	namespace string
+}
+
+// certVerifier is a tls.Config.VerifyPeerCertificate callback.
+type certVerifier func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error
+
+// newConfig builds a *vault.Config for the configured Vault server, wiring up
+// the CA bundle (from CABundle or CAProvider) and SNI ServerName when TLS
+// validation material has been configured.
+func (c *client) newConfig(ctx context.Context) (*vault.Config, error) {
+	cfg := vault.DefaultConfig()
+	cfg.Address = c.store.Server
+
+	pins := c.caPins()
+	hasCA := c.store.CAProvider != nil || len(c.store.CABundle) > 0
+	hasClientTLS := c.store.ClientTLS != nil
+	hasTLSConfig := hasCA || hasClientTLS || len(pins) > 0
+
+	if !hasTLSConfig {
+		if err := c.applyHTTPClientConfig(cfg); err != nil {
+			return nil, err
+		}
+		return cfg, nil
+	}
+
+	transport, ok := cfg.HttpClient.Transport.(*http.Transport)
+	if !ok {
+		if err := c.applyHTTPClientConfig(cfg); err != nil {
+			return nil, err
+		}
+		return cfg, nil
+	}
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	reloadEnabled := c.store.CAProvider != nil && c.store.CABundleReloadInterval != nil
+
+	if hasCA {
+		pool, err := c.caCertPool(ctx)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig.RootCAs = pool
+
+		// Best-effort: derive the SNI ServerName from the configured Server
+		// URL. An unparsable Server is not fatal here, newConfig's caller
+		// will surface the same error when it tries to use the address.
+		if u, perr := url.Parse(c.store.Server); perr == nil {
+			transport.TLSClientConfig.ServerName = u.Hostname()
+		}
+	}
+
+	if hasClientTLS {
+		cert, err := c.clientCertificate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	var pinVerifier certVerifier
+	if len(pins) > 0 {
+		// Without a static CA pool (either because none is configured, or
+		// because CA reload has taken over verification itself) there is no
+		// chain for Go to verify, so the pin check must parse rawCerts
+		// itself rather than relying on verifiedChains.
+		chainless := !hasCA || reloadEnabled
+		pinVerifier = buildPinVerifier(pins, chainless)
+	}
+
+	switch {
+	case reloadEnabled:
+		// Reloading takes over verification entirely (including any
+		// configured pin), since it must run its own chain verification
+		// against the live-reloaded pool instead of Go's built-in one.
+		startCAReloader(c, transport, c.store.CABundleReloadInterval.Duration, pinVerifier)
+	case pinVerifier != nil:
+		// Without a configured CA pool there is nothing for normal chain
+		// verification to trust against; the pin becomes the sole trust
+		// anchor, mirroring how k3s bootstraps against a self-signed CA
+		// using only its SHA-256 fingerprint.
+		if transport.TLSClientConfig.RootCAs == nil {
+			transport.TLSClientConfig.InsecureSkipVerify = true //nolint:gosec // verification is performed by VerifyPeerCertificate below
+		}
+		transport.TLSClientConfig.VerifyPeerCertificate = pinVerifier
+	}
+
+	if err := c.applyHTTPClientConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// caPins returns the configured CAPinSHA256 values merged with any pins
+// embedded in a "#sha256:<hex>[,<hex>...]" fragment on the Server URL.
+func (c *client) caPins() []string {
+	pins := make([]string, 0, len(c.store.CAPinSHA256))
+	pins = append(pins, c.store.CAPinSHA256...)
+	pins = append(pins, pinsFromServerURL(c.store.Server)...)
+	return pins
+}
+
+// caCertPool builds the *x509.CertPool used to validate the Vault server's
+// TLS certificate from the configured CABundle and/or CAProvider. The
+// CAProvider's Mode decides whether the resulting pool replaces, extends, or
+// ignores the certificates resolved from CAProvider relative to the OS trust
+// store.
+func (c *client) caCertPool(ctx context.Context) (*x509.CertPool, error) {
+	mode := esv1.CAProviderModeReplace
+	if c.store.CAProvider != nil && c.store.CAProvider.Mode != "" {
+		mode = c.store.CAProvider.Mode
+	}
+
+	var pool *x509.CertPool
+	var err error
+	configured := false
+
+	switch mode {
+	case esv1.CAProviderModeAppend, esv1.CAProviderModeSystemOnly:
+		pool, err = systemCertPool()
+		if err != nil {
+			return nil, err
+		}
+		configured = true
+	default:
+		pool = x509.NewCertPool()
+	}
+
+	if len(c.store.CABundle) > 0 {
+		if !pool.AppendCertsFromPEM(c.store.CABundle) {
+			return nil, fmt.Errorf("failed to parse caBundle PEM data")
+		}
+		configured = true
+	}
+
+	if c.store.CAProvider != nil && mode != esv1.CAProviderModeSystemOnly {
+		pem, err := c.fetchCAProviderPEM(ctx, c.store.CAProvider)
+		if err != nil {
+			return nil, err
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("failed to parse CA certificate from %s %q", c.store.CAProvider.Type, c.store.CAProvider.Name)
+		}
+		configured = true
+	}
+
+	if !configured {
+		return nil, nil
+	}
+
+	return pool, nil
+}
+
+// systemCertPool returns the OS trust store, falling back to an empty pool
+// (never nil) when the platform reports no error but no pool either.
+func systemCertPool() (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load system cert pool: %w", err)
+	}
+	if pool == nil {
+		pool = x509.NewCertPool()
+	}
+	return pool, nil
+}
+
+// clientCertificate resolves the configured ClientTLS CertSecretRef and
+// KeySecretRef and parses them into a tls.Certificate for mutual TLS to
+// Vault. The same helper backs the "cert" auth method.
+func (c *client) clientCertificate(ctx context.Context) (tls.Certificate, error) {
+	if c.store.ClientTLS == nil || c.store.ClientTLS.CertSecretRef == nil || c.store.ClientTLS.KeySecretRef == nil {
+		return tls.Certificate{}, fmt.Errorf("clientTls requires both certSecretRef and keySecretRef")
+	}
+
+	certPEM, err := c.fetchSecretKey(ctx, c.store.ClientTLS.CertSecretRef)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to fetch clientTls certificate: %w", err)
+	}
+	keyPEM, err := c.fetchSecretKey(ctx, c.store.ClientTLS.KeySecretRef)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to fetch clientTls key: %w", err)
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to parse clientTls certificate/key pair: %w", err)
+	}
+	return cert, nil
+}
+
+// fetchSecretKey resolves a SecretKeySelector to the raw bytes stored under
+// its Key in the referenced Secret.
+func (c *client) fetchSecretKey(ctx context.Context, ref *esv1.SecretKeySelector) ([]byte, error) {
+	namespace := c.namespace
+	if c.storeKind == esv1.ClusterSecretStoreKind && ref.Namespace != nil {
+		namespace = *ref.Namespace
+	}
+	name := types.NamespacedName{Namespace: namespace, Name: ref.Name}
+
+	var sec corev1.Secret
+	if err := c.kube.Get(ctx, name, &sec); err != nil {
+		return nil, fmt.Errorf("failed to fetch Secret %s: %w", name, err)
+	}
+	val, ok := sec.Data[ref.Key]
+	if !ok {
+		return nil, fmt.Errorf("no key %q found in Secret %s", ref.Key, name)
+	}
+	return val, nil
+}
+
+// fetchCAProviderPEM resolves the given CAProvider to its raw PEM bytes via
+// the Kubernetes API.
+func (c *client) fetchCAProviderPEM(ctx context.Context, provider *esv1.CAProvider) ([]byte, error) {
+	namespace := c.namespace
+	if c.storeKind == esv1.ClusterSecretStoreKind && provider.Namespace != nil {
+		namespace = *provider.Namespace
+	}
+	ref := types.NamespacedName{Namespace: namespace, Name: provider.Name}
+
+	switch provider.Type {
+	case esv1.CAProviderTypeConfigMap:
+		var cm corev1.ConfigMap
+		if err := c.kube.Get(ctx, ref, &cm); err != nil {
+			return nil, fmt.Errorf("failed to fetch CA ConfigMap %s: %w", ref, err)
+		}
+		val, ok := cm.Data[provider.Key]
+		if !ok {
+			return nil, fmt.Errorf("no key %q found in ConfigMap %s", provider.Key, ref)
+		}
+		return []byte(val), nil
+	case esv1.CAProviderTypeSecret:
+		var sec corev1.Secret
+		if err := c.kube.Get(ctx, ref, &sec); err != nil {
+			return nil, fmt.Errorf("failed to fetch CA Secret %s: %w", ref, err)
+		}
+		val, ok := sec.Data[provider.Key]
+		if !ok {
+			return nil, fmt.Errorf("no key %q found in Secret %s", provider.Key, ref)
+		}
+		return val, nil
+	default:
+		return nil, fmt.Errorf("unsupported CAProvider type %q", provider.Type)
+	}
+}
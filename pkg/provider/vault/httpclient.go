@@ -0,0 +1,162 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+	vault "github.com/hashicorp/vault/api"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+// applyHTTPClientConfig tunes cfg's HTTP transport and retry/backoff
+// behavior according to the store's HTTPClient stanza, if any.
+func (c *client) applyHTTPClientConfig(cfg *vault.Config) error {
+	hc := c.store.HTTPClient
+	if hc == nil {
+		return nil
+	}
+
+	if hc.Timeout != nil {
+		cfg.Timeout = hc.Timeout.Duration
+	}
+
+	if transport, ok := cfg.HttpClient.Transport.(*http.Transport); ok {
+		if hc.TLSHandshakeTimeout != nil {
+			transport.TLSHandshakeTimeout = hc.TLSHandshakeTimeout.Duration
+		}
+		if hc.IdleConnTimeout != nil {
+			transport.IdleConnTimeout = hc.IdleConnTimeout.Duration
+		}
+		if hc.MaxIdleConnsPerHost > 0 {
+			transport.MaxIdleConnsPerHost = hc.MaxIdleConnsPerHost
+		}
+		transport.DisableKeepAlives = hc.DisableKeepAlives
+
+		proxyFunc, err := buildProxyFunc(hc.Proxy)
+		if err != nil {
+			return err
+		}
+		if proxyFunc != nil {
+			transport.Proxy = proxyFunc
+		}
+	}
+
+	if hc.Retry != nil {
+		if hc.Retry.MaxRetries != nil {
+			cfg.MaxRetries = *hc.Retry.MaxRetries
+		}
+		cfg.MinRetryWait = durationOrDefault(hc.Retry.MinBackoff, cfg.MinRetryWait)
+		cfg.MaxRetryWait = durationOrDefault(hc.Retry.MaxBackoff, cfg.MaxRetryWait)
+		cfg.CheckRetry = buildCheckRetry(hc.Retry)
+	}
+
+	return nil
+}
+
+// applyHeaders adds the store's configured HTTPClient.Headers to every
+// request made by vc, e.g. a per-store X-Vault-Namespace override or custom
+// audit headers.
+func (c *client) applyHeaders(vc *vault.Client) {
+	if c.store.HTTPClient == nil || len(c.store.HTTPClient.Headers) == 0 {
+		return
+	}
+
+	headers := vc.Headers()
+	if headers == nil {
+		headers = make(http.Header, len(c.store.HTTPClient.Headers))
+	}
+	for k, v := range c.store.HTTPClient.Headers {
+		headers.Set(k, v)
+	}
+	vc.SetHeaders(headers)
+}
+
+// buildProxyFunc builds an http.Transport.Proxy function from a
+// VaultProxyConfig. A nil return means "leave the transport's existing Proxy
+// setting untouched".
+func buildProxyFunc(p *esv1.VaultProxyConfig) (func(*http.Request) (*url.URL, error), error) {
+	if p == nil {
+		return nil, nil
+	}
+	if p.FromEnvironment {
+		return http.ProxyFromEnvironment, nil
+	}
+	if p.URL == "" {
+		return nil, nil
+	}
+
+	proxyURL, err := url.Parse(p.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse httpClient.proxy.url: %w", err)
+	}
+
+	noProxy := make(map[string]struct{}, len(p.NoProxy))
+	for _, host := range p.NoProxy {
+		noProxy[host] = struct{}{}
+	}
+
+	return func(req *http.Request) (*url.URL, error) {
+		if _, skip := noProxy[req.URL.Hostname()]; skip {
+			return nil, nil
+		}
+		return proxyURL, nil
+	}, nil
+}
+
+// buildCheckRetry returns a retryablehttp.CheckRetry that retries on 5xx
+// responses and/or connection errors according to retry's toggles. Both
+// toggles default to true, the same as the vault client's own
+// retryablehttp.DefaultRetryPolicy, so configuring an unrelated field on
+// VaultRetryConfig (e.g. minBackoff) doesn't silently turn off retries a
+// user never asked to disable.
+func buildCheckRetry(retry *esv1.VaultRetryConfig) retryablehttp.CheckRetry {
+	retryOn5xx := boolOrDefault(retry.RetryOn5xx, true)
+	retryOnConnectionError := boolOrDefault(retry.RetryOnConnectionError, true)
+
+	return func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		if err != nil {
+			return retryOnConnectionError, nil
+		}
+		if resp != nil && resp.StatusCode >= http.StatusInternalServerError {
+			return retryOn5xx, nil
+		}
+		return false, nil
+	}
+}
+
+func durationOrDefault(d *metav1.Duration, def time.Duration) time.Duration {
+	if d == nil {
+		return def
+	}
+	return d.Duration
+}
+
+func boolOrDefault(b *bool, def bool) bool {
+	if b == nil {
+		return def
+	}
+	return *b
+}
@@ -0,0 +1,128 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+func TestBuildProxyFunc_NilProxy_LeavesTransportUntouched(t *testing.T) {
+	fn, err := buildProxyFunc(nil)
+	require.NoError(t, err)
+	require.Nil(t, fn)
+}
+
+func TestBuildProxyFunc_FromEnvironment(t *testing.T) {
+	fn, err := buildProxyFunc(&esv1.VaultProxyConfig{FromEnvironment: true})
+	require.NoError(t, err)
+	require.NotNil(t, fn)
+}
+
+func TestBuildProxyFunc_ExplicitURL_RespectsNoProxy(t *testing.T) {
+	fn, err := buildProxyFunc(&esv1.VaultProxyConfig{
+		URL:     "http://proxy.example.com:8080",
+		NoProxy: []string{"vault.internal"},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, fn)
+
+	skipped, err := fn(&http.Request{URL: &url.URL{Host: "vault.internal"}})
+	require.NoError(t, err)
+	require.Nil(t, skipped)
+
+	proxied, err := fn(&http.Request{URL: &url.URL{Host: "vault.example.com"}})
+	require.NoError(t, err)
+	require.NotNil(t, proxied)
+	require.Equal(t, "proxy.example.com:8080", proxied.Host)
+}
+
+func TestBuildProxyFunc_InvalidURL(t *testing.T) {
+	_, err := buildProxyFunc(&esv1.VaultProxyConfig{URL: "://bad"})
+	require.Error(t, err)
+}
+
+func TestBuildCheckRetry_ExplicitToggles(t *testing.T) {
+	disabled := false
+	retry := &esv1.VaultRetryConfig{RetryOn5xx: boolPtr(true), RetryOnConnectionError: &disabled}
+	checkRetry := buildCheckRetry(retry)
+
+	retryOn5xx, err := checkRetry(context.Background(), &http.Response{StatusCode: http.StatusBadGateway}, nil)
+	require.NoError(t, err)
+	require.True(t, retryOn5xx)
+
+	retryOnErr, err := checkRetry(context.Background(), nil, errors.New("dial tcp: connection refused"))
+	require.NoError(t, err)
+	require.False(t, retryOnErr)
+
+	retryOn4xx, err := checkRetry(context.Background(), &http.Response{StatusCode: http.StatusBadRequest}, nil)
+	require.NoError(t, err)
+	require.False(t, retryOn4xx)
+}
+
+// TestBuildCheckRetry_UnsetTogglesDefaultToTrue guards against setting an
+// unrelated VaultRetryConfig field (e.g. minBackoff) silently disabling
+// retries the user never asked to turn off.
+func TestBuildCheckRetry_UnsetTogglesDefaultToTrue(t *testing.T) {
+	checkRetry := buildCheckRetry(&esv1.VaultRetryConfig{})
+
+	retryOn5xx, err := checkRetry(context.Background(), &http.Response{StatusCode: http.StatusBadGateway}, nil)
+	require.NoError(t, err)
+	require.True(t, retryOn5xx)
+
+	retryOnErr, err := checkRetry(context.Background(), nil, errors.New("dial tcp: connection refused"))
+	require.NoError(t, err)
+	require.True(t, retryOnErr)
+}
+
+func boolPtr(b bool) *bool { return &b }
+
+func TestApplyHTTPClientConfig_MaxRetriesZeroDisablesRetries(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	zero := 0
+	c := &client{store: &esv1.VaultProvider{
+		Server: srv.URL,
+		HTTPClient: &esv1.VaultHTTPClient{
+			Retry: &esv1.VaultRetryConfig{MaxRetries: &zero},
+		},
+	}}
+
+	cfg, err := c.newConfig(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 0, cfg.MaxRetries)
+}
+
+func TestApplyHTTPClientConfig_MaxRetriesUnsetKeepsDefault(t *testing.T) {
+	c := &client{store: &esv1.VaultProvider{
+		Server: "https://vault.example.com",
+		HTTPClient: &esv1.VaultHTTPClient{
+			Retry: &esv1.VaultRetryConfig{},
+		},
+	}}
+
+	cfg, err := c.newConfig(context.Background())
+	require.NoError(t, err)
+	require.NotEqual(t, 0, cfg.MaxRetries)
+}
@@ -0,0 +1,110 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// pinFragmentPrefix is the "#sha256:<hex>[,<hex>...]" fragment format used to
+// embed one or more acceptable CA pins directly in the Server URL.
+const pinFragmentPrefix = "sha256:"
+
+// pinsFromServerURL extracts SHA-256 pins from a "#sha256:<hex>[,<hex>...]"
+// fragment on server, if present. A malformed or absent fragment yields no
+// pins rather than an error, consistent with newConfig's other best-effort
+// URL parsing.
+func pinsFromServerURL(server string) []string {
+	u, err := url.Parse(server)
+	if err != nil || !strings.HasPrefix(u.Fragment, pinFragmentPrefix) {
+		return nil
+	}
+
+	raw := strings.TrimPrefix(u.Fragment, pinFragmentPrefix)
+	if raw == "" {
+		return nil
+	}
+
+	return strings.Split(raw, ",")
+}
+
+// buildPinVerifier returns a tls.Config.VerifyPeerCertificate callback that
+// requires at least one certificate in the presented chain to match a
+// configured pin. When chainless is true (no RootCAs were configured, so
+// normal chain verification is skipped), the presented rawCerts are parsed
+// and checked directly instead of relying on verifiedChains.
+func buildPinVerifier(pins []string, chainless bool) certVerifier {
+	normalized := normalizePins(pins)
+
+	return func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+		if chainless {
+			for i, raw := range rawCerts {
+				cert, err := x509.ParseCertificate(raw)
+				if err != nil {
+					continue
+				}
+				if matchesPin(cert, i == 0, normalized) {
+					return nil
+				}
+			}
+			return fmt.Errorf("vault server certificate does not match any configured CAPinSHA256 pin")
+		}
+
+		for _, chain := range verifiedChains {
+			for i, cert := range chain {
+				if matchesPin(cert, i == 0, normalized) {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("vault server certificate does not match any configured CAPinSHA256 pin")
+	}
+}
+
+// matchesPin reports whether cert's DER fingerprint, or (for the leaf
+// certificate) its SubjectPublicKeyInfo fingerprint, is present in pins.
+func matchesPin(cert *x509.Certificate, isLeaf bool, pins map[string]struct{}) bool {
+	if _, ok := pins[hexSHA256(cert.Raw)]; ok {
+		return true
+	}
+	if isLeaf {
+		if _, ok := pins[hexSHA256(cert.RawSubjectPublicKeyInfo)]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func hexSHA256(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func normalizePins(pins []string) map[string]struct{} {
+	out := make(map[string]struct{}, len(pins))
+	for _, p := range pins {
+		p = strings.ToLower(strings.TrimSpace(p))
+		if p == "" {
+			continue
+		}
+		out[p] = struct{}{}
+	}
+	return out
+}
@@ -0,0 +1,98 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"crypto/x509"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPinsFromServerURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		server string
+		want   []string
+	}{
+		{
+			name:   "single pin",
+			server: "https://vault.example.com:8200#sha256:abcd1234",
+			want:   []string{"abcd1234"},
+		},
+		{
+			name:   "multiple pins",
+			server: "https://vault.example.com:8200#sha256:abcd1234,deadbeef",
+			want:   []string{"abcd1234", "deadbeef"},
+		},
+		{
+			name:   "no fragment",
+			server: "https://vault.example.com:8200",
+			want:   nil,
+		},
+		{
+			name:   "fragment without sha256 prefix is ignored",
+			server: "https://vault.example.com:8200#not-a-pin",
+			want:   nil,
+		},
+		{
+			name:   "invalid URL yields no pins",
+			server: "://invalid-url",
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, pinsFromServerURL(tt.server))
+		})
+	}
+}
+
+func TestBuildPinVerifier_Chainless(t *testing.T) {
+	ca, key := generateSelfSignedCA(t, "ca")
+	leaf := issueLeaf(t, ca, key, "vault.example.com")
+
+	leafCert, err := x509.ParseCertificate(leaf)
+	require.NoError(t, err)
+	pin := hexSHA256(leafCert.Raw)
+
+	verify := buildPinVerifier([]string{pin}, true)
+	require.NoError(t, verify([][]byte{leaf}, nil))
+
+	verify = buildPinVerifier([]string{"0000"}, true)
+	require.Error(t, verify([][]byte{leaf}, nil))
+}
+
+func TestBuildPinVerifier_WithVerifiedChains(t *testing.T) {
+	ca, key := generateSelfSignedCA(t, "ca")
+	leaf := issueLeaf(t, ca, key, "vault.example.com")
+	leafCert, err := x509.ParseCertificate(leaf)
+	require.NoError(t, err)
+
+	pin := hexSHA256(ca.Raw)
+	verify := buildPinVerifier([]string{pin}, false)
+	require.NoError(t, verify(nil, [][]*x509.Certificate{{leafCert, ca}}))
+}
+
+func TestNormalizePins(t *testing.T) {
+	pins := normalizePins([]string{" ABCD ", "", "deadBEEF"})
+	_, hasAbcd := pins["abcd"]
+	_, hasDeadbeef := pins["deadbeef"]
+	assert.True(t, hasAbcd)
+	assert.True(t, hasDeadbeef)
+	assert.Len(t, pins, 2)
+}
@@ -0,0 +1,357 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	vault "github.com/hashicorp/vault/api"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+	"github.com/external-secrets/external-secrets/pkg/generator"
+)
+
+// PKIIssueRequest describes a request to Vault's pki secrets engine "issue"
+// endpoint, i.e. Vault generates both the private key and the certificate.
+type PKIIssueRequest struct {
+	// Mount is the path the pki secrets engine is mounted at, e.g. "pki".
+	Mount string
+	// Role is the pki role to issue against.
+	Role string
+
+	CommonName string
+	AltNames   []string
+	IPSANs     []string
+	URISANs    []string
+	TTL        string
+
+	// Format is one of "pem", "pem_bundle" or "der". Defaults to "pem".
+	Format string
+}
+
+// PKISignRequest describes a request to Vault's pki secrets engine "sign" (or
+// "sign-verbatim") endpoint, i.e. the caller already holds a CSR and only
+// wants it signed by Vault's CA.
+type PKISignRequest struct {
+	Mount string
+	Role  string
+
+	CSRPEM     string
+	CommonName string
+	AltNames   []string
+	IPSANs     []string
+	URISANs    []string
+	TTL        string
+
+	// Verbatim routes the request through "sign-verbatim" instead of
+	// "sign", so the CSR's subject and SAN fields are used as-is rather
+	// than constrained by the named role.
+	Verbatim bool
+
+	Format string
+}
+
+// PKICertificate is the materialized result of a pki issue/sign call.
+type PKICertificate struct {
+	Certificate  string
+	PrivateKey   string
+	CAChain      []string
+	IssuingCA    string
+	SerialNumber string
+	Expiration   time.Time
+}
+
+// IssuePKICertificate issues a new short-lived certificate (and private key)
+// from Vault's pki secrets engine.
+func (c *client) IssuePKICertificate(ctx context.Context, req PKIIssueRequest) (*PKICertificate, error) {
+	vc, err := c.newVaultClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("%s/issue/%s", req.Mount, req.Role)
+	data := map[string]interface{}{
+		"common_name": req.CommonName,
+	}
+	if len(req.AltNames) > 0 {
+		data["alt_names"] = joinCSV(req.AltNames)
+	}
+	if len(req.IPSANs) > 0 {
+		data["ip_sans"] = joinCSV(req.IPSANs)
+	}
+	if len(req.URISANs) > 0 {
+		data["uri_sans"] = joinCSV(req.URISANs)
+	}
+	if req.TTL != "" {
+		data["ttl"] = req.TTL
+	}
+	data["format"] = defaultFormat(req.Format)
+
+	secret, err := vc.Logical().WriteWithContext(ctx, path, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue pki certificate at %q: %w", path, err)
+	}
+	return parsePKISecret(secret)
+}
+
+// SignPKICertificate signs a caller-supplied CSR against Vault's pki secrets
+// engine, using the "sign" endpoint (constrained by req.Role) or, when
+// req.Verbatim is set, the "sign-verbatim" endpoint.
+func (c *client) SignPKICertificate(ctx context.Context, req PKISignRequest) (*PKICertificate, error) {
+	vc, err := c.newVaultClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := "sign"
+	if req.Verbatim {
+		endpoint = "sign-verbatim"
+	}
+	path := fmt.Sprintf("%s/%s/%s", req.Mount, endpoint, req.Role)
+
+	data := map[string]interface{}{
+		"csr": req.CSRPEM,
+	}
+	if req.CommonName != "" {
+		data["common_name"] = req.CommonName
+	}
+	if len(req.AltNames) > 0 {
+		data["alt_names"] = joinCSV(req.AltNames)
+	}
+	if len(req.IPSANs) > 0 {
+		data["ip_sans"] = joinCSV(req.IPSANs)
+	}
+	if len(req.URISANs) > 0 {
+		data["uri_sans"] = joinCSV(req.URISANs)
+	}
+	if req.TTL != "" {
+		data["ttl"] = req.TTL
+	}
+	data["format"] = defaultFormat(req.Format)
+
+	secret, err := vc.Logical().WriteWithContext(ctx, path, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign pki certificate at %q: %w", path, err)
+	}
+	return parsePKISecret(secret)
+}
+
+// SecretData materializes p into the keys a Secret written by this provider
+// should contain: "tls.crt", "tls.key", "ca.crt", "chain.crt", plus
+// "serial_number" and "expiration" for status reporting.
+func (p *PKICertificate) SecretData() map[string][]byte {
+	out := map[string][]byte{
+		"tls.crt":       []byte(p.Certificate),
+		"serial_number": []byte(p.SerialNumber),
+		"expiration":    []byte(p.Expiration.UTC().Format(time.RFC3339)),
+	}
+	if p.PrivateKey != "" {
+		out["tls.key"] = []byte(p.PrivateKey)
+	}
+	if p.IssuingCA != "" {
+		out["ca.crt"] = []byte(p.IssuingCA)
+	}
+	if len(p.CAChain) > 0 {
+		chain := ""
+		for _, c := range p.CAChain {
+			chain += c + "\n"
+		}
+		out["chain.crt"] = []byte(chain)
+	}
+	return out
+}
+
+// ShouldReissue reports whether a certificate with the given expiration
+// should be re-issued now, i.e. its remaining lifetime has dropped below
+// 1/3 of its original ttl.
+func ShouldReissue(expiration time.Time, ttl time.Duration) bool {
+	if ttl <= 0 {
+		return false
+	}
+	return time.Until(expiration) < ttl/3
+}
+
+func parsePKISecret(secret *vault.Secret) (*PKICertificate, error) {
+	if secret == nil || secret.Data == nil {
+		return nil, fmt.Errorf("vault pki response contained no data")
+	}
+
+	cert := &PKICertificate{
+		Certificate:  stringField(secret.Data, "certificate"),
+		PrivateKey:   stringField(secret.Data, "private_key"),
+		IssuingCA:    stringField(secret.Data, "issuing_ca"),
+		SerialNumber: stringField(secret.Data, "serial_number"),
+	}
+	if chain, ok := secret.Data["ca_chain"].([]interface{}); ok {
+		for _, c := range chain {
+			if s, ok := c.(string); ok {
+				cert.CAChain = append(cert.CAChain, s)
+			}
+		}
+	}
+	// vault/api.ParseSecret decodes the response body with
+	// json.Decoder.UseNumber(), so numeric fields surface as json.Number,
+	// never float64.
+	if exp, ok := secret.Data["expiration"].(json.Number); ok {
+		if sec, err := exp.Int64(); err == nil {
+			cert.Expiration = time.Unix(sec, 0)
+		}
+	}
+	if cert.Certificate == "" {
+		return nil, fmt.Errorf("vault pki response did not contain a certificate")
+	}
+	return cert, nil
+}
+
+func stringField(data map[string]interface{}, key string) string {
+	if v, ok := data[key].(string); ok {
+		return v
+	}
+	return ""
+}
+
+func defaultFormat(format string) string {
+	if format == "" {
+		return "pem"
+	}
+	return format
+}
+
+func joinCSV(values []string) string {
+	out := ""
+	for i, v := range values {
+		if i > 0 {
+			out += ","
+		}
+		out += v
+	}
+	return out
+}
+
+func init() {
+	generator.Register(genv1alpha1.VaultPKIKind, &PKIGenerator{})
+}
+
+// PKIGenerator implements generator.Generator for the VaultPKI generator
+// CRD, issuing or signing a short-lived X.509 certificate from a Vault pki
+// secrets engine mount.
+type PKIGenerator struct{}
+
+// Generate issues or signs a certificate per the VaultPKI spec encoded in
+// jsonSpec, unless existing already holds a certificate that doesn't yet
+// need reissuing (see needsReissue), in which case it is returned
+// unchanged.
+func (g *PKIGenerator) Generate(ctx context.Context, jsonSpec *apiextensionsv1.JSON, kube kclient.Client, namespace string, existing map[string][]byte) (map[string][]byte, error) {
+	if jsonSpec == nil {
+		return nil, fmt.Errorf("vault pki generator: missing spec")
+	}
+	var spec genv1alpha1.VaultPKISpec
+	if err := json.Unmarshal(jsonSpec.Raw, &spec); err != nil {
+		return nil, fmt.Errorf("vault pki generator: failed to parse spec: %w", err)
+	}
+
+	if !needsReissue(existing, spec.TTL) {
+		return existing, nil
+	}
+
+	c := &client{
+		kube:      kube,
+		store:     &spec.Provider,
+		storeKind: esv1.SecretStoreKind,
+		namespace: namespace,
+	}
+
+	var cert *PKICertificate
+	var err error
+	if spec.CSRRef != nil {
+		csrPEM, ferr := c.fetchSecretKey(ctx, spec.CSRRef)
+		if ferr != nil {
+			return nil, fmt.Errorf("vault pki generator: failed to fetch csrRef: %w", ferr)
+		}
+		cert, err = c.SignPKICertificate(ctx, PKISignRequest{
+			Mount:      spec.Mount,
+			Role:       spec.Role,
+			CSRPEM:     string(csrPEM),
+			CommonName: spec.CommonName,
+			AltNames:   spec.AltNames,
+			IPSANs:     spec.IPSANs,
+			URISANs:    spec.URISANs,
+			TTL:        spec.TTL,
+			Verbatim:   spec.Verbatim,
+			Format:     spec.Format,
+		})
+	} else {
+		cert, err = c.IssuePKICertificate(ctx, PKIIssueRequest{
+			Mount:      spec.Mount,
+			Role:       spec.Role,
+			CommonName: spec.CommonName,
+			AltNames:   spec.AltNames,
+			IPSANs:     spec.IPSANs,
+			URISANs:    spec.URISANs,
+			TTL:        spec.TTL,
+			Format:     spec.Format,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return cert.SecretData(), nil
+}
+
+// needsReissue reports whether existing is empty, or holds an expiration
+// that ShouldReissue determines is due for renewal given spec's requested
+// ttl. A missing or unparsable expiration/ttl is treated as needing reissue,
+// since there's nothing to safely compare against. Only the "expiration" key
+// is consulted; whether existing holds a "tls.crt" is not checked.
+func needsReissue(existing map[string][]byte, ttl string) bool {
+	if len(existing) == 0 {
+		return true
+	}
+	expRaw, ok := existing["expiration"]
+	if !ok {
+		return true
+	}
+	expiration, err := time.Parse(time.RFC3339, string(expRaw))
+	if err != nil {
+		return true
+	}
+	d, err := time.ParseDuration(ttl)
+	if err != nil {
+		return true
+	}
+	return ShouldReissue(expiration, d)
+}
+
+// newVaultClient builds an authenticated-config (but not yet logged in)
+// Vault API client from the store's connection settings. Auth methods layer
+// their login on top of the returned client.
+func (c *client) newVaultClient(ctx context.Context) (*vault.Client, error) {
+	cfg, err := c.newConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	vc, err := vault.NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	c.applyHeaders(vc)
+	return vc, nil
+}
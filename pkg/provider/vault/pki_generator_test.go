@@ -0,0 +1,88 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+	"github.com/external-secrets/external-secrets/pkg/generator"
+)
+
+func TestNeedsReissue(t *testing.T) {
+	require.True(t, needsReissue(nil, "1h"))
+	require.True(t, needsReissue(map[string][]byte{}, "1h"))
+	require.True(t, needsReissue(map[string][]byte{"expiration": []byte("not-a-time")}, "1h"))
+	require.True(t, needsReissue(map[string][]byte{
+		"expiration": []byte(time.Now().Add(time.Hour).Format(time.RFC3339)),
+	}, "not-a-duration"))
+
+	freshExpiration := time.Now().Add(23 * time.Hour).Format(time.RFC3339)
+	require.False(t, needsReissue(map[string][]byte{"expiration": []byte(freshExpiration)}, "24h"))
+
+	staleExpiration := time.Now().Add(time.Minute).Format(time.RFC3339)
+	require.True(t, needsReissue(map[string][]byte{"expiration": []byte(staleExpiration)}, "24h"))
+}
+
+func TestPKIGenerator_IsRegistered(t *testing.T) {
+	g, err := generator.Get(genv1alpha1.VaultPKIKind)
+	require.NoError(t, err)
+	require.NotNil(t, g)
+}
+
+func TestPKIGenerator_Generate_IssuesAndSkipsUntilDue(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"data":{"certificate":"CERT","private_key":"KEY","expiration":%d}}`,
+			time.Now().Add(24*time.Hour).Unix())))
+	}))
+	defer srv.Close()
+
+	spec := genv1alpha1.VaultPKISpec{
+		Provider: esv1.VaultProvider{Server: srv.URL},
+		Mount:    "pki",
+		Role:     "my-role",
+		TTL:      "24h",
+	}
+	raw, err := json.Marshal(spec)
+	require.NoError(t, err)
+
+	g := &PKIGenerator{}
+	fakeClient := fake.NewClientBuilder().Build()
+
+	data, err := g.Generate(context.Background(), &apiextensionsv1.JSON{Raw: raw}, fakeClient, "ns", nil)
+	require.NoError(t, err)
+	require.Equal(t, []byte("CERT"), data["tls.crt"])
+	require.Equal(t, 1, requests)
+
+	// Re-running with the still-fresh existing data should not call Vault again.
+	data2, err := g.Generate(context.Background(), &apiextensionsv1.JSON{Raw: raw}, fakeClient, "ns", data)
+	require.NoError(t, err)
+	require.Equal(t, data, data2)
+	require.Equal(t, 1, requests)
+}
@@ -0,0 +1,248 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// defaultCAReloadInterval is used whenever CABundleReloadInterval is set but
+// its duration is zero.
+const defaultCAReloadInterval = 5 * time.Minute
+
+var caReloadTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "externalsecrets",
+	Subsystem: "vault",
+	Name:      "ca_reload_total",
+	Help:      "Number of Vault provider CA bundle reloads that swapped in a changed bundle (result=success) or failed (result=error).",
+}, []string{"result"})
+
+func init() {
+	prometheus.MustRegister(caReloadTotal)
+}
+
+// reloaderEntry bundles a reload goroutine's live CA pool with the cancel
+// func that stops it, so a store's reloader can be torn down explicitly
+// (see StopCAReloader) instead of only ever ending at process exit.
+type reloaderEntry struct {
+	pool   *atomic.Pointer[x509.CertPool]
+	cancel context.CancelFunc
+}
+
+// reloaderPools holds one reloaderEntry per distinct Vault store, keyed by
+// reloaderKey, so that every client built for the same store shares a
+// single background reload goroutine and sees the same live pool instead of
+// each newConfig call spawning its own.
+var reloaderPools sync.Map // string -> *reloaderEntry
+
+// reloaderBaseCtx is the long-lived context CA-reload goroutines derive
+// their own (cancellable) context from. It defaults to context.Background()
+// until SetReloaderContext is called, e.g. from the controller's
+// SetupWithManager with the Manager's own context, so reloaders stop
+// cleanly on controller shutdown rather than leaking past it.
+var reloaderBaseCtx atomic.Pointer[context.Context]
+
+// SetReloaderContext supplies the base context CA-reload goroutines are
+// derived from. Call it once, early, with a context that is canceled on
+// controller shutdown (e.g. the context a controller-runtime Manager
+// passes to Start). Reloaders already running are not retroactively
+// re-parented; call it before the first SecretStore with
+// caBundleReloadInterval set is reconciled.
+func SetReloaderContext(ctx context.Context) {
+	reloaderBaseCtx.Store(&ctx)
+}
+
+func baseReloaderContext() context.Context {
+	if ctx := reloaderBaseCtx.Load(); ctx != nil {
+		return *ctx
+	}
+	return context.Background()
+}
+
+// startCAReloader arranges for transport to validate the Vault server's
+// certificate against a pool that is periodically refreshed from the
+// client's CAProvider, without restarting the controller.
+//
+// tls.Config.GetConfigForClient is only consulted by the server side of a
+// TLS handshake; it is never read when http.Transport dials out to Vault.
+// To actually affect outbound connections, verification is done by hand in
+// VerifyPeerCertificate against a live *x509.CertPool referenced through an
+// atomic.Pointer, which a single background goroutine per store keeps
+// up to date.
+func startCAReloader(c *client, transport *http.Transport, interval time.Duration, extraVerify certVerifier) {
+	if interval <= 0 {
+		interval = defaultCAReloadInterval
+	}
+
+	ctx, cancel := context.WithCancel(baseReloaderContext())
+	candidate := &reloaderEntry{
+		pool:   newCertPoolPointer(transport.TLSClientConfig.RootCAs),
+		cancel: cancel,
+	}
+
+	key := reloaderKey(c)
+	entryAny, alreadyRunning := reloaderPools.LoadOrStore(key, candidate)
+	entry, _ := entryAny.(*reloaderEntry)
+	if alreadyRunning {
+		// Another caller already has a reloader running for this store;
+		// the context/cancel we just built for candidate is unused.
+		cancel()
+	}
+
+	serverName := transport.TLSClientConfig.ServerName
+	transport.TLSClientConfig.RootCAs = nil
+	transport.TLSClientConfig.InsecureSkipVerify = true //nolint:gosec // verification is performed in buildLiveCAVerifier against the live-reloaded pool
+	transport.TLSClientConfig.VerifyPeerCertificate = buildLiveCAVerifier(entry.pool, serverName, extraVerify)
+
+	if !alreadyRunning {
+		go reloadCALoop(ctx, c, entry.pool, interval)
+	}
+}
+
+// StopCAReloader stops the background CA-reload goroutine for c's store, if
+// one is running, and removes its entry from reloaderPools. Controllers
+// should call this when a SecretStore with caBundleReloadInterval set is
+// deleted (or the field is cleared), so a reloader doesn't keep polling a
+// CA bundle and logging errors for a store that no longer exists.
+func StopCAReloader(c *client) {
+	entryAny, ok := reloaderPools.LoadAndDelete(reloaderKey(c))
+	if !ok {
+		return
+	}
+	if entry, ok := entryAny.(*reloaderEntry); ok {
+		entry.cancel()
+	}
+}
+
+// reloaderKey identifies the logical Vault store a reloader belongs to, so
+// that repeated client/config construction for the same store reuses one
+// goroutine and one live pool instead of leaking a new goroutine per call.
+func reloaderKey(c *client) string {
+	provider := ""
+	if c.store.CAProvider != nil {
+		provider = string(c.store.CAProvider.Type) + "/" + c.store.CAProvider.Name
+	}
+	return c.storeKind + "|" + c.namespace + "|" + c.store.Server + "|" + provider
+}
+
+func newCertPoolPointer(pool *x509.CertPool) *atomic.Pointer[x509.CertPool] {
+	p := &atomic.Pointer[x509.CertPool]{}
+	p.Store(pool)
+	return p
+}
+
+func reloadCALoop(ctx context.Context, c *client, pool *atomic.Pointer[x509.CertPool], interval time.Duration) {
+	logger := log.FromContext(ctx).WithName("vault-ca-reloader")
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var fingerprint string
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			fingerprint = reloadCAOnce(ctx, logger, c, pool, fingerprint)
+		}
+	}
+}
+
+// reloadCAOnce re-reads the CA bundle and swaps it into pool if the
+// fingerprint of the new bundle differs from lastFingerprint. It returns the
+// fingerprint that should be compared against on the next tick.
+func reloadCAOnce(ctx context.Context, logger logr.Logger, c *client, pool *atomic.Pointer[x509.CertPool], lastFingerprint string) string {
+	newPool, err := c.caCertPool(ctx)
+	if err != nil {
+		caReloadTotal.WithLabelValues("error").Inc()
+		logger.Error(err, "failed to reload vault CA bundle")
+		return lastFingerprint
+	}
+	if newPool == nil {
+		return lastFingerprint
+	}
+
+	fingerprint := fingerprintCertPool(newPool)
+	if fingerprint == lastFingerprint {
+		return lastFingerprint
+	}
+
+	pool.Store(newPool)
+
+	caReloadTotal.WithLabelValues("success").Inc()
+	logger.Info("reloaded vault CA bundle", "fingerprint", fingerprint)
+
+	return fingerprint
+}
+
+// buildLiveCAVerifier returns a tls.Config.VerifyPeerCertificate callback
+// that verifies the presented chain against whatever pool.Load() returns at
+// handshake time, then (if extraVerify is set, e.g. CA pinning) runs that
+// check too. It is meant to be used together with InsecureSkipVerify: true,
+// since it replaces Go's built-in verification rather than supplementing it.
+func buildLiveCAVerifier(pool *atomic.Pointer[x509.CertPool], serverName string, extraVerify certVerifier) certVerifier {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		certs := make([]*x509.Certificate, 0, len(rawCerts))
+		for _, raw := range rawCerts {
+			cert, err := x509.ParseCertificate(raw)
+			if err != nil {
+				return fmt.Errorf("failed to parse certificate presented by vault server: %w", err)
+			}
+			certs = append(certs, cert)
+		}
+		if len(certs) == 0 {
+			return fmt.Errorf("vault server presented no certificates")
+		}
+
+		if roots := pool.Load(); roots != nil {
+			intermediates := x509.NewCertPool()
+			for _, cert := range certs[1:] {
+				intermediates.AddCert(cert)
+			}
+			opts := x509.VerifyOptions{
+				Roots:         roots,
+				Intermediates: intermediates,
+				DNSName:       serverName,
+			}
+			if _, err := certs[0].Verify(opts); err != nil {
+				return fmt.Errorf("failed to verify vault server certificate: %w", err)
+			}
+		}
+
+		if extraVerify != nil {
+			return extraVerify(rawCerts, nil)
+		}
+		return nil
+	}
+}
+
+// fingerprintCertPool returns a stable hash identifying the contents of
+// pool. *x509.CertPool does not expose its raw DER certificates, so the
+// pool's subject list is used as a cheap proxy for "did this change".
+func fingerprintCertPool(pool *x509.CertPool) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%v", pool.Subjects()))) //nolint:staticcheck // Subjects() is the only stable pool introspection available
+	return hex.EncodeToString(sum[:])
+}
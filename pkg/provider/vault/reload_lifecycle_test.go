@@ -0,0 +1,76 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+func reloadTestTransport() *http.Transport {
+	return &http.Transport{TLSClientConfig: &tls.Config{MinVersion: tls.VersionTLS12}}
+}
+
+func TestStartCAReloader_DedupesPerStore(t *testing.T) {
+	c := &client{
+		store:     &esv1.VaultProvider{Server: "https://vault.example.com"},
+		storeKind: esv1.SecretStoreKind,
+		namespace: "ns",
+	}
+	defer StopCAReloader(c)
+
+	startCAReloader(c, reloadTestTransport(), time.Hour, nil)
+	entryAny, ok := reloaderPools.Load(reloaderKey(c))
+	require.True(t, ok)
+	first := entryAny.(*reloaderEntry)
+
+	startCAReloader(c, reloadTestTransport(), time.Hour, nil)
+	entryAny, ok = reloaderPools.Load(reloaderKey(c))
+	require.True(t, ok)
+	require.Same(t, first, entryAny.(*reloaderEntry), "a second startCAReloader call for the same store must reuse the existing entry, not spawn another goroutine")
+}
+
+func TestStopCAReloader_RemovesEntryAndRestartsFresh(t *testing.T) {
+	c := &client{
+		store:     &esv1.VaultProvider{Server: "https://vault-stop.example.com"},
+		storeKind: esv1.SecretStoreKind,
+		namespace: "ns",
+	}
+
+	startCAReloader(c, reloadTestTransport(), time.Hour, nil)
+	entryAny, ok := reloaderPools.Load(reloaderKey(c))
+	require.True(t, ok)
+	entry := entryAny.(*reloaderEntry)
+
+	StopCAReloader(c)
+
+	_, stillPresent := reloaderPools.Load(reloaderKey(c))
+	require.False(t, stillPresent, "StopCAReloader must remove the store's entry so a later reconcile starts clean")
+
+	// Starting the reloader again after Stop must build a new entry (with
+	// its own context and cancel func) rather than reuse the stopped one.
+	// Cancellation of the stopped entry's context is not observed here.
+	startCAReloader(c, reloadTestTransport(), time.Hour, nil)
+	entryAny, ok = reloaderPools.Load(reloaderKey(c))
+	require.True(t, ok)
+	require.NotSame(t, entry, entryAny.(*reloaderEntry), "a reloader restarted after Stop must get a fresh entry/context")
+	StopCAReloader(c)
+}
@@ -0,0 +1,124 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestBuildLiveCAVerifier_ReloadsPool exercises the fix for the reloader
+// actually affecting outbound dials: tls.Config.GetConfigForClient is never
+// consulted by a client-side handshake, so the pool swap has to happen
+// inside VerifyPeerCertificate instead. This pins down that a certificate
+// rejected against the old pool is accepted as soon as the live pool is
+// swapped, with no new transport or verifier being created.
+func TestBuildLiveCAVerifier_ReloadsPool(t *testing.T) {
+	oldCA, oldKey := generateSelfSignedCA(t, "old-ca")
+	newCA, newKey := generateSelfSignedCA(t, "new-ca")
+
+	pool := &atomic.Pointer[x509.CertPool]{}
+	initial := x509.NewCertPool()
+	initial.AddCert(oldCA)
+	pool.Store(initial)
+
+	verify := buildLiveCAVerifier(pool, "vault.example.com", nil)
+
+	oldLeaf := issueLeaf(t, oldCA, oldKey, "vault.example.com")
+	require.NoError(t, verify([][]byte{oldLeaf}, nil), "leaf signed by the currently trusted CA should verify")
+
+	newLeaf := issueLeaf(t, newCA, newKey, "vault.example.com")
+	require.Error(t, verify([][]byte{newLeaf}, nil), "leaf signed by a not-yet-trusted CA must be rejected")
+
+	rotated := x509.NewCertPool()
+	rotated.AddCert(newCA)
+	pool.Store(rotated)
+
+	require.NoError(t, verify([][]byte{newLeaf}, nil), "after the live pool is swapped the new leaf must verify without rebuilding the transport")
+	require.Error(t, verify([][]byte{oldLeaf}, nil), "the old leaf is no longer trusted once the pool has rotated away from it")
+}
+
+// TestBuildLiveCAVerifier_ExtraVerify confirms a pin check layered on top of
+// live CA reload still runs and can reject an otherwise-trusted chain.
+func TestBuildLiveCAVerifier_ExtraVerify(t *testing.T) {
+	ca, key := generateSelfSignedCA(t, "ca")
+	pool := &atomic.Pointer[x509.CertPool]{}
+	rootPool := x509.NewCertPool()
+	rootPool.AddCert(ca)
+	pool.Store(rootPool)
+
+	leaf := issueLeaf(t, ca, key, "vault.example.com")
+
+	rejecting := func(_ [][]byte, _ [][]*x509.Certificate) error {
+		return errExtraVerifyRejected
+	}
+	verify := buildLiveCAVerifier(pool, "vault.example.com", rejecting)
+	require.ErrorIs(t, verify([][]byte{leaf}, nil), errExtraVerifyRejected)
+}
+
+var errExtraVerifyRejected = errors.New("rejected by extraVerify")
+
+func generateSelfSignedCA(t *testing.T, cn string) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, key
+}
+
+func issueLeaf(t *testing.T, ca *x509.Certificate, caKey *ecdsa.PrivateKey, cn string) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: cn},
+		DNSNames:     []string{cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca, &key.PublicKey, caKey)
+	require.NoError(t, err)
+	return der
+}